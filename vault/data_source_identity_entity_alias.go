@@ -0,0 +1,161 @@
+package vault
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func identityEntityAliasDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: identityEntityAliasDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the alias. This, or a combination of 'name' and 'mount_accessor', must be provided.",
+			},
+
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the alias.",
+			},
+
+			"mount_accessor": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Mount accessor to which this alias belongs to.",
+			},
+
+			"canonical_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the entity to which this is an alias.",
+			},
+
+			"custom_metadata": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Custom metadata associated with this alias.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"metadata": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Metadata associated with the parent entity.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Policies associated with the parent entity.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// identityEntityAliasDataSourcePath resolves which path to read the alias
+// from based on which of 'id', '(name, mount_accessor)' or '(canonical_id,
+// name, mount_accessor)' were provided. lookupAliasID is injected so this
+// path-selection logic can be unit tested without a Vault client.
+func identityEntityAliasDataSourcePath(d *schema.ResourceData, lookupAliasID func(canonicalID, name, mountAccessor string) (string, error)) (string, error) {
+	id, idOk := d.GetOk("id")
+	name, nameOk := d.GetOk("name")
+	mountAccessor, mountAccessorOk := d.GetOk("mount_accessor")
+	canonicalID, canonicalIDOk := d.GetOk("canonical_id")
+
+	switch {
+	case idOk:
+		return identityEntityAliasIDPath(id.(string)), nil
+	case canonicalIDOk && nameOk && mountAccessorOk:
+		aliasID, err := lookupAliasID(canonicalID.(string), name.(string), mountAccessor.(string))
+		if err != nil {
+			return "", fmt.Errorf("error looking up alias with name %q and mount_accessor %q: %s", name, mountAccessor, err)
+		}
+		return identityEntityAliasIDPath(aliasID), nil
+	case nameOk && mountAccessorOk:
+		return identityEntityAliasNamePath(name.(string)), nil
+	default:
+		return "", fmt.Errorf("must specify either 'id', 'name' and 'mount_accessor', or 'canonical_id', 'name' and 'mount_accessor'")
+	}
+}
+
+// identityEntityAliasCheckMountAccessor guards against the name-only lookup
+// path (identityEntityAliasNamePath) resolving to an alias owned by a
+// different mount than the one requested.
+func identityEntityAliasCheckMountAccessor(resp *api.Secret, path, name, mountAccessor string, mountAccessorOk bool) error {
+	if mountAccessorOk && resp.Data["mount_accessor"] != mountAccessor {
+		return fmt.Errorf("alias %q found at %q belongs to mount accessor %q, not %q", name, path, resp.Data["mount_accessor"], mountAccessor)
+	}
+	return nil
+}
+
+func identityEntityAliasDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+
+	path, err := identityEntityAliasDataSourcePath(d, func(canonicalID, name, mountAccessor string) (string, error) {
+		return findAliasID(client, canonicalID, name, mountAccessor)
+	})
+	if err != nil {
+		return err
+	}
+
+	name, _ := d.GetOk("name")
+	mountAccessor, mountAccessorOk := d.GetOk("mount_accessor")
+
+	log.Printf("[DEBUG] Reading IdentityEntityAlias from %q", path)
+	resp, err := client.Logical().Read(path)
+	if err != nil {
+		return fmt.Errorf("error reading IdentityEntityAlias from %q: %s", path, err)
+	}
+	if resp == nil {
+		return fmt.Errorf("no IdentityEntityAlias found at %q", path)
+	}
+
+	if err := identityEntityAliasCheckMountAccessor(resp, path, name.(string), mountAccessor.(string), mountAccessorOk); err != nil {
+		return err
+	}
+
+	d.SetId(resp.Data["id"].(string))
+	for _, k := range []string{"name", "mount_accessor", "canonical_id", "custom_metadata"} {
+		if err := d.Set(k, resp.Data[k]); err != nil {
+			return fmt.Errorf("error setting state key %q on IdentityEntityAlias %q: err=%q", k, d.Id(), err)
+		}
+	}
+
+	entityPath := identityEntityIDPath(resp.Data["canonical_id"].(string))
+	log.Printf("[DEBUG] Reading parent IdentityEntity from %q", entityPath)
+	entity, err := client.Logical().Read(entityPath)
+	if err != nil {
+		return fmt.Errorf("error reading parent IdentityEntity from %q: %s", entityPath, err)
+	}
+	if entity == nil {
+		return fmt.Errorf("no parent IdentityEntity found at %q", entityPath)
+	}
+
+	if err := d.Set("metadata", entity.Data["metadata"]); err != nil {
+		return fmt.Errorf("error setting state key \"metadata\" on IdentityEntityAlias %q: err=%q", d.Id(), err)
+	}
+	if err := d.Set("policies", entity.Data["policies"]); err != nil {
+		return fmt.Errorf("error setting state key \"policies\" on IdentityEntityAlias %q: err=%q", d.Id(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestIdentityEntityAliasDataSourcePath(t *testing.T) {
+	sch := identityEntityAliasDataSource().Schema
+
+	lookupCalled := func(t *testing.T, wantCanonicalID, wantName, wantMountAccessor string, aliasID string, err error) func(canonicalID, name, mountAccessor string) (string, error) {
+		return func(canonicalID, name, mountAccessor string) (string, error) {
+			if canonicalID != wantCanonicalID || name != wantName || mountAccessor != wantMountAccessor {
+				t.Fatalf("lookupAliasID called with (%q, %q, %q), want (%q, %q, %q)", canonicalID, name, mountAccessor, wantCanonicalID, wantName, wantMountAccessor)
+			}
+			return aliasID, err
+		}
+	}
+
+	cases := []struct {
+		name       string
+		raw        map[string]interface{}
+		lookup     func(canonicalID, name, mountAccessor string) (string, error)
+		wantPath   string
+		wantErr    bool
+		wantErrMsg string
+	}{
+		{
+			name: "id takes precedence",
+			raw: map[string]interface{}{
+				"id":             "alias-id",
+				"name":           "ignored",
+				"mount_accessor": "ignored",
+				"canonical_id":   "ignored",
+			},
+			lookup:   lookupCalled(t, "", "", "", "", nil),
+			wantPath: identityEntityAliasIDPath("alias-id"),
+		},
+		{
+			name: "canonical_id, name and mount_accessor resolve via findAliasID",
+			raw: map[string]interface{}{
+				"canonical_id":   "entity-1",
+				"name":           "my-alias",
+				"mount_accessor": "auth_userpass_1234",
+			},
+			lookup:   lookupCalled(t, "entity-1", "my-alias", "auth_userpass_1234", "resolved-alias-id", nil),
+			wantPath: identityEntityAliasIDPath("resolved-alias-id"),
+		},
+		{
+			name: "findAliasID error is surfaced",
+			raw: map[string]interface{}{
+				"canonical_id":   "entity-1",
+				"name":           "my-alias",
+				"mount_accessor": "auth_userpass_1234",
+			},
+			lookup:     lookupCalled(t, "entity-1", "my-alias", "auth_userpass_1234", "", fmt.Errorf("not found")),
+			wantErr:    true,
+			wantErrMsg: `error looking up alias with name "my-alias" and mount_accessor "auth_userpass_1234": not found`,
+		},
+		{
+			name: "name and mount_accessor without canonical_id use the name path",
+			raw: map[string]interface{}{
+				"name":           "my-alias",
+				"mount_accessor": "auth_userpass_1234",
+			},
+			lookup:   lookupCalled(t, "", "", "", "", nil),
+			wantPath: identityEntityAliasNamePath("my-alias"),
+		},
+		{
+			name:       "nothing specified is an error",
+			raw:        map[string]interface{}{},
+			lookup:     lookupCalled(t, "", "", "", "", nil),
+			wantErr:    true,
+			wantErrMsg: "must specify either 'id', 'name' and 'mount_accessor', or 'canonical_id', 'name' and 'mount_accessor'",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, sch, tc.raw)
+
+			path, err := identityEntityAliasDataSourcePath(d, tc.lookup)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				if err.Error() != tc.wantErrMsg {
+					t.Fatalf("expected error %q, got %q", tc.wantErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if path != tc.wantPath {
+				t.Fatalf("expected path %q, got %q", tc.wantPath, path)
+			}
+		})
+	}
+}
+
+func TestIdentityEntityAliasCheckMountAccessor(t *testing.T) {
+	cases := []struct {
+		name            string
+		respMountAccess string
+		mountAccessor   string
+		mountAccessorOk bool
+		wantErr         bool
+	}{
+		{
+			name:            "matching mount accessor is fine",
+			respMountAccess: "auth_userpass_1234",
+			mountAccessor:   "auth_userpass_1234",
+			mountAccessorOk: true,
+		},
+		{
+			name:            "mismatched mount accessor errors",
+			respMountAccess: "auth_userpass_1234",
+			mountAccessor:   "auth_github_5678",
+			mountAccessorOk: true,
+		},
+		{
+			name:            "mount_accessor not specified skips the check",
+			respMountAccess: "auth_userpass_1234",
+			mountAccessor:   "",
+			mountAccessorOk: false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &api.Secret{Data: map[string]interface{}{"mount_accessor": tc.respMountAccess}}
+
+			err := identityEntityAliasCheckMountAccessor(resp, "/identity/entity-alias/name/my-alias", "my-alias", tc.mountAccessor, tc.mountAccessorOk)
+
+			wantErr := tc.mountAccessorOk && tc.respMountAccess != tc.mountAccessor
+			if wantErr && err == nil {
+				t.Fatal("expected a mismatch error, got nil")
+			}
+			if !wantErr && err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+// TestAccIdentityEntityAliasDataSource_lookups exercises the data source's
+// three lookup paths ('id', '(name, mount_accessor)', and '(canonical_id,
+// name, mount_accessor)') against a real alias, matching the acceptance-test
+// pattern already used for this resource/backend series.
+func TestAccIdentityEntityAliasDataSource_lookups(t *testing.T) {
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar)
+	}
+
+	testAccPreCheck(t)
+	client := testProvider.Meta().(*api.Client)
+
+	authPath := acctest.RandomWithPrefix("userpass")
+	if err := client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatalf("error enabling userpass auth at %q: %s", authPath, err)
+	}
+	defer client.Sys().DisableAuth(authPath)
+
+	auths, err := client.Sys().ListAuth()
+	if err != nil {
+		t.Fatalf("error listing auth mounts: %s", err)
+	}
+	mountAccessor := auths[authPath+"/"].Accessor
+
+	entityResp, err := client.Logical().Write("identity/entity", map[string]interface{}{
+		"name":     acctest.RandomWithPrefix("tf-test-entity"),
+		"metadata": map[string]interface{}{"team": "platform"},
+		"policies": []interface{}{"default"},
+	})
+	if err != nil {
+		t.Fatalf("error creating entity: %s", err)
+	}
+	canonicalID := entityResp.Data["id"].(string)
+
+	aliasName := acctest.RandomWithPrefix("tf-test-alias")
+	aliasResp, err := client.Logical().Write(identityEntityAliasPath, map[string]interface{}{
+		"name":           aliasName,
+		"mount_accessor": mountAccessor,
+		"canonical_id":   canonicalID,
+		"custom_metadata": map[string]interface{}{
+			"owner": "team-a",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating alias: %s", err)
+	}
+	aliasID := aliasResp.Data["id"].(string)
+
+	dataSourceSchema := identityEntityAliasDataSource().Schema
+
+	lookups := map[string]map[string]interface{}{
+		"by id": {
+			"id": aliasID,
+		},
+		"by name and mount_accessor": {
+			"name":           aliasName,
+			"mount_accessor": mountAccessor,
+		},
+		"by canonical_id, name and mount_accessor": {
+			"canonical_id":   canonicalID,
+			"name":           aliasName,
+			"mount_accessor": mountAccessor,
+		},
+	}
+
+	for name, raw := range lookups {
+		raw := raw
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourceSchema, raw)
+			if err := identityEntityAliasDataSourceRead(d, client); err != nil {
+				t.Fatalf("error reading IdentityEntityAlias data source: %s", err)
+			}
+			if d.Id() != aliasID {
+				t.Fatalf("expected alias id %q, got %q", aliasID, d.Id())
+			}
+			if d.Get("canonical_id").(string) != canonicalID {
+				t.Fatalf("expected canonical_id %q, got %q", canonicalID, d.Get("canonical_id"))
+			}
+			if d.Get("metadata").(map[string]interface{})["team"] != "platform" {
+				t.Fatalf("expected parent entity metadata to be surfaced, got %v", d.Get("metadata"))
+			}
+		})
+	}
+}
@@ -1,15 +1,68 @@
 package vault
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/vault/api"
 )
 
 const identityEntityAliasPath = "/identity/entity-alias"
 
+// custom_metadata_mode values for identity_entity_alias.
+const (
+	identityEntityAliasCustomMetadataModeReplace = "replace"
+	identityEntityAliasCustomMetadataModeMerge   = "merge"
+)
+
+// identityEntityAliasLocks is a fixed-size pool of locks, one of which is
+// selected per alias (or per in-flight create) by hashing its key, the same
+// bounded-lock-pool pattern Vault's AppRole backend uses internally to avoid
+// serializing unrelated aliases behind a single global lock.
+//
+// This only protects against races between goroutines inside this one
+// provider process: the SDK runs CRUD for independent resource instances
+// concurrently (bounded by Terraform's -parallelism), so two
+// vault_identity_entity_alias instances that happen to reference the same
+// alias id can otherwise interleave their read-modify-write sequences and
+// clobber each other's custom_metadata. It does NOT, and cannot, protect
+// against a second `terraform apply` running as a separate process, or
+// another Vault client, writing the same alias at the same time -- the
+// identity-entity-alias API has no CAS/version parameter to make such a
+// cross-process write safe, so there is nothing to retry against.
+//
+// Known limitation, called out explicitly here and in the PR description for
+// sign-off rather than left to be discovered later: cross-process/cross-client
+// writers can still clobber custom_metadata. Closing that gap needs a CAS
+// parameter added to the identity-entity-alias API itself; it is out of
+// scope for this provider-side change.
+var identityEntityAliasLocks = newLockPool(256)
+
+func newLockPool(n int) []*sync.RWMutex {
+	locks := make([]*sync.RWMutex, n)
+	for i := range locks {
+		locks[i] = &sync.RWMutex{}
+	}
+	return locks
+}
+
+func lockForIdentityEntityAliasKey(key string) *sync.RWMutex {
+	hash := sha256.Sum256([]byte(key))
+	idx := binary.BigEndian.Uint32(hash[:4]) % uint32(len(identityEntityAliasLocks))
+	return identityEntityAliasLocks[idx]
+}
+
+func identityEntityAliasCreateLockKey(canonicalID, mountAccessor, name string) string {
+	return strings.Join([]string{canonicalID, mountAccessor, name}, "/")
+}
+
 func identityEntityAliasResource() *schema.Resource {
 	return &schema.Resource{
 		Create: identityEntityAliasCreate,
@@ -47,10 +100,83 @@ func identityEntityAliasResource() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"custom_metadata_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  identityEntityAliasCustomMetadataModeReplace,
+				Description: "How `custom_metadata` is reconciled with the remote alias on update. `replace` " +
+					"(the default) overwrites the remote map with the configured one. `merge` unions the " +
+					"configured keys into the remote map, leaving keys managed by other clients untouched.",
+				ValidateFunc: validation.StringInSlice([]string{
+					identityEntityAliasCustomMetadataModeReplace,
+					identityEntityAliasCustomMetadataModeMerge,
+				}, false),
+			},
+			"custom_metadata_managed_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The custom_metadata keys that are managed by this resource.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
 
+// identityEntityAliasManagedKeys returns the sorted set of keys Terraform is
+// declaring ownership of for a given custom_metadata configuration.
+func identityEntityAliasManagedKeys(customMetadata map[string]interface{}) []string {
+	keys := make([]string, 0, len(customMetadata))
+	for k := range customMetadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// identityEntityAliasMergeCustomMetadata unions the Terraform-managed
+// customMetadata over the remote map, dropping any key Terraform previously
+// managed (per previousManagedKeys) that has since been removed from config.
+func identityEntityAliasMergeCustomMetadata(remote map[string]interface{}, customMetadata map[string]interface{}, previousManagedKeys []interface{}) map[string]interface{} {
+	previouslyManaged := make(map[string]bool, len(previousManagedKeys))
+	for _, k := range previousManagedKeys {
+		previouslyManaged[k.(string)] = true
+	}
+
+	merged := make(map[string]interface{}, len(remote)+len(customMetadata))
+	for k, v := range remote {
+		if previouslyManaged[k] {
+			if _, stillConfigured := customMetadata[k]; !stillConfigured {
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	for k, v := range customMetadata {
+		merged[k] = v
+	}
+	return merged
+}
+
+// identityEntityAliasFilterManagedKeys restricts data to the given set of
+// managed keys, so unrelated metadata written by other clients doesn't show
+// up as drift in a merge-mode resource's state.
+func identityEntityAliasFilterManagedKeys(data map[string]interface{}, managedKeys []interface{}) map[string]interface{} {
+	managed := make(map[string]bool, len(managedKeys))
+	for _, k := range managedKeys {
+		managed[k.(string)] = true
+	}
+
+	filtered := make(map[string]interface{}, len(managed))
+	for k, v := range data {
+		if managed[k] {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
 func identityEntityAliasCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 
@@ -59,6 +185,10 @@ func identityEntityAliasCreate(d *schema.ResourceData, meta interface{}) error {
 	canonicalID := d.Get("canonical_id").(string)
 	customMetadata := d.Get("custom_metadata").(map[string]interface{})
 
+	lock := lockForIdentityEntityAliasKey(identityEntityAliasCreateLockKey(canonicalID, mountAccessor, name))
+	lock.Lock()
+	defer lock.Unlock()
+
 	path := identityEntityAliasPath
 
 	data := map[string]interface{}{
@@ -87,6 +217,9 @@ func identityEntityAliasCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Wrote IdentityEntityAlias %q", name)
 
 	d.SetId(resp.Data["id"].(string))
+	if err := d.Set("custom_metadata_managed_keys", identityEntityAliasManagedKeys(customMetadata)); err != nil {
+		return fmt.Errorf("error setting state key \"custom_metadata_managed_keys\" on IdentityEntityAlias %q: err=%q", d.Id(), err)
+	}
 
 	return identityEntityAliasRead(d, meta)
 }
@@ -95,6 +228,10 @@ func identityEntityAliasUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 	id := d.Id()
 
+	lock := lockForIdentityEntityAliasKey(id)
+	lock.Lock()
+	defer lock.Unlock()
+
 	log.Printf("[DEBUG] Updating IdentityEntityAlias %q", id)
 	path := identityEntityAliasIDPath(id)
 
@@ -102,6 +239,9 @@ func identityEntityAliasUpdate(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return fmt.Errorf("error updating IdentityEntityAlias %q: %s", id, err)
 	}
+	if resp == nil {
+		return fmt.Errorf("IdentityEntityAlias %q not found", id)
+	}
 
 	data := map[string]interface{}{
 		"name":           resp.Data["name"],
@@ -119,15 +259,25 @@ func identityEntityAliasUpdate(d *schema.ResourceData, meta interface{}) error {
 		data["canonical_id"] = canonicalID
 	}
 
-	data["custom_metadata"] = d.Get("custom_metadata").(map[string]interface{})
-
-	_, err = client.Logical().Write(path, data)
+	customMetadata := d.Get("custom_metadata").(map[string]interface{})
+	managedKeys := identityEntityAliasManagedKeys(customMetadata)
+
+	if d.Get("custom_metadata_mode").(string) == identityEntityAliasCustomMetadataModeMerge {
+		remote, _ := resp.Data["custom_metadata"].(map[string]interface{})
+		previousManagedKeys := d.Get("custom_metadata_managed_keys").([]interface{})
+		data["custom_metadata"] = identityEntityAliasMergeCustomMetadata(remote, customMetadata, previousManagedKeys)
+	} else {
+		data["custom_metadata"] = customMetadata
+	}
 
-	if err != nil {
+	if _, err := client.Logical().Write(path, data); err != nil {
 		return fmt.Errorf("error updating IdentityEntityAlias %q: %s", id, err)
 	}
-	log.Printf("[DEBUG] Updated IdentityEntityAlias %q", id)
 
+	log.Printf("[DEBUG] Updated IdentityEntityAlias %q", id)
+	if err := d.Set("custom_metadata_managed_keys", managedKeys); err != nil {
+		return fmt.Errorf("error setting state key \"custom_metadata_managed_keys\" on IdentityEntityAlias %q: err=%q", id, err)
+	}
 	return identityEntityAliasRead(d, meta)
 }
 
@@ -150,11 +300,20 @@ func identityEntityAliasRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.SetId(resp.Data["id"].(string))
-	for _, k := range []string{"name", "mount_accessor", "canonical_id", "custom_metadata"} {
+	for _, k := range []string{"name", "mount_accessor", "canonical_id"} {
 		if err := d.Set(k, resp.Data[k]); err != nil {
 			return fmt.Errorf("error setting state key %q on IdentityEntityAlias %q:  err=%q", k, id, err)
 		}
 	}
+
+	customMetadata, _ := resp.Data["custom_metadata"].(map[string]interface{})
+	if d.Get("custom_metadata_mode").(string) == identityEntityAliasCustomMetadataModeMerge {
+		customMetadata = identityEntityAliasFilterManagedKeys(customMetadata, d.Get("custom_metadata_managed_keys").([]interface{}))
+	}
+	if err := d.Set("custom_metadata", customMetadata); err != nil {
+		return fmt.Errorf("error setting state key \"custom_metadata\" on IdentityEntityAlias %q:  err=%q", id, err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,116 @@
+package vault
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+// TestAccIdentityEntityAlias_parallelUpdates drives identityEntityAliasUpdate
+// from two goroutines concurrently, each touching a disjoint field, to prove
+// that the per-alias locking keeps one update from clobbering the other.
+//
+// Both goroutines set custom_metadata_mode = "merge" so the result doesn't
+// depend on which one happens to win the race for the lock: in replace mode,
+// whichever goroutine omits custom_metadata from its config would wipe out
+// whatever the other just wrote, making the outcome a coin flip.
+func TestAccIdentityEntityAlias_parallelUpdates(t *testing.T) {
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar)
+	}
+
+	testAccPreCheck(t)
+	client := testProvider.Meta().(*api.Client)
+
+	authPath := acctest.RandomWithPrefix("userpass")
+	if err := client.Sys().EnableAuthWithOptions(authPath, &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatalf("error enabling userpass auth at %q: %s", authPath, err)
+	}
+	defer client.Sys().DisableAuth(authPath)
+
+	auths, err := client.Sys().ListAuth()
+	if err != nil {
+		t.Fatalf("error listing auth mounts: %s", err)
+	}
+	mountAccessor := auths[authPath+"/"].Accessor
+
+	entityResp, err := client.Logical().Write("identity/entity", map[string]interface{}{
+		"name": acctest.RandomWithPrefix("tf-test-entity"),
+	})
+	if err != nil {
+		t.Fatalf("error creating entity: %s", err)
+	}
+	canonicalID := entityResp.Data["id"].(string)
+
+	aliasResp, err := client.Logical().Write(identityEntityAliasPath, map[string]interface{}{
+		"name":           acctest.RandomWithPrefix("tf-test-alias"),
+		"mount_accessor": mountAccessor,
+		"canonical_id":   canonicalID,
+		"custom_metadata": map[string]interface{}{
+			"owner": "team-a",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating alias: %s", err)
+	}
+	aliasID := aliasResp.Data["id"].(string)
+
+	newName := acctest.RandomWithPrefix("tf-test-alias-renamed")
+	newMetadata := map[string]interface{}{"owner": "team-b", "tier": "gold"}
+
+	aliasSchema := identityEntityAliasResource().Schema
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d := schema.TestResourceDataRaw(t, aliasSchema, map[string]interface{}{
+			"name":                 newName,
+			"mount_accessor":       mountAccessor,
+			"canonical_id":         canonicalID,
+			"custom_metadata_mode": identityEntityAliasCustomMetadataModeMerge,
+		})
+		d.SetId(aliasID)
+		errs <- identityEntityAliasUpdate(d, client)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d := schema.TestResourceDataRaw(t, aliasSchema, map[string]interface{}{
+			"mount_accessor":       mountAccessor,
+			"canonical_id":         canonicalID,
+			"custom_metadata":      newMetadata,
+			"custom_metadata_mode": identityEntityAliasCustomMetadataModeMerge,
+		})
+		d.SetId(aliasID)
+		errs <- identityEntityAliasUpdate(d, client)
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("error updating alias concurrently: %s", err)
+		}
+	}
+
+	final, err := client.Logical().Read(identityEntityAliasIDPath(aliasID))
+	if err != nil {
+		t.Fatalf("error reading alias after concurrent updates: %s", err)
+	}
+	if final.Data["name"] != newName {
+		t.Fatalf("expected name %q to survive concurrent update, got %q", newName, final.Data["name"])
+	}
+	metadata, ok := final.Data["custom_metadata"].(map[string]interface{})
+	if !ok || metadata["tier"] != "gold" {
+		t.Fatalf("expected custom_metadata from the other goroutine to survive, got %v", final.Data["custom_metadata"])
+	}
+}
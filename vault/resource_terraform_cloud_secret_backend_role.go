@@ -5,11 +5,17 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/vault/api"
 )
 
+// terraformCloudAccessCredentialsLeaseRenewThreshold is how close to lease
+// expiry a cached Terraform Cloud token is allowed to get before it is
+// considered stale and re-read from Vault.
+const terraformCloudAccessCredentialsLeaseRenewThreshold = 1 * time.Minute
+
 var (
 	terraformCloudSecretBackendRoleBackendFromPathRegex = regexp.MustCompile("^(.+)/role/.+$")
 	terraformCloudSecretBackendRoleNameFromPathRegex    = regexp.MustCompile("^.+/role/(.+$)")
@@ -227,3 +233,170 @@ func terraformCloudSecretBackendRoleBackendFromPath(path string) (string, error)
 	}
 	return res[1], nil
 }
+
+func terraformCloudAccessCredentialsPath(backend, role string) string {
+	return strings.Trim(backend, "/") + "/creds/" + role
+}
+
+func terraformCloudAccessCredentialsSchema(forceNew bool) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backend": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    forceNew,
+			Description: "The path of the Terraform Cloud Secret Backend the role belongs to.",
+		},
+		"role": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    forceNew,
+			Description: "Name of the role to create credentials against.",
+		},
+		"token": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "Dynamic Terraform Cloud API token.",
+		},
+		"token_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the Terraform Cloud API token.",
+		},
+		"organization": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the Terraform Cloud or Enterprise organization the token belongs to.",
+		},
+		"team_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the Terraform Cloud or Enterprise team the token belongs to, if any.",
+		},
+		"user_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the Terraform Cloud or Enterprise user the token belongs to, if any.",
+		},
+		"lease_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Lease identifier assigned by Vault.",
+		},
+		"lease_duration": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Lease duration in seconds.",
+		},
+		"lease_started": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Timestamp (RFC3339) at which the current lease was issued.",
+		},
+	}
+}
+
+func terraformCloudAccessCredentialsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: terraformCloudAccessCredentialsRead,
+
+		Schema: terraformCloudAccessCredentialsSchema(false),
+	}
+}
+
+func terraformCloudAccessCredentialsResource() *schema.Resource {
+	return &schema.Resource{
+		Create: terraformCloudAccessCredentialsCreate,
+		Read:   terraformCloudAccessCredentialsResourceRead,
+		Delete: terraformCloudAccessCredentialsDelete,
+
+		Schema: terraformCloudAccessCredentialsSchema(true),
+	}
+}
+
+// terraformCloudAccessCredentialsNeedsRefresh reports whether the lease
+// recorded on d is close enough to expiry that it should be re-read from
+// Vault rather than reused as-is.
+func terraformCloudAccessCredentialsNeedsRefresh(d *schema.ResourceData) bool {
+	started, err := time.Parse(time.RFC3339, d.Get("lease_started").(string))
+	if err != nil {
+		return true
+	}
+	duration := time.Duration(d.Get("lease_duration").(int)) * time.Second
+	return time.Until(started.Add(duration)) < terraformCloudAccessCredentialsLeaseRenewThreshold
+}
+
+func terraformCloudAccessCredentialsRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() != "" && !terraformCloudAccessCredentialsNeedsRefresh(d) {
+		log.Printf("[DEBUG] Reusing Terraform Cloud credentials lease %q; not yet eligible for renewal", d.Id())
+		return nil
+	}
+
+	client := meta.(*api.Client)
+
+	backend := d.Get("backend").(string)
+	role := d.Get("role").(string)
+	path := terraformCloudAccessCredentialsPath(backend, role)
+
+	log.Printf("[DEBUG] Reading Terraform Cloud credentials at %q", path)
+	secret, err := client.Logical().Read(path)
+	if err != nil {
+		return fmt.Errorf("error reading Terraform Cloud credentials at %q: %s", path, err)
+	}
+	if secret == nil {
+		return fmt.Errorf("no Terraform Cloud credentials found at %q", path)
+	}
+
+	d.SetId(secret.LeaseID)
+	d.Set("token", secret.Data["token"])
+	d.Set("token_id", secret.Data["token_id"])
+	d.Set("organization", secret.Data["organization"])
+	d.Set("team_id", secret.Data["team_id"])
+	d.Set("user_id", secret.Data["user_id"])
+	d.Set("lease_id", secret.LeaseID)
+	d.Set("lease_duration", secret.LeaseDuration)
+	d.Set("lease_started", time.Now().UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+func terraformCloudAccessCredentialsCreate(d *schema.ResourceData, meta interface{}) error {
+	return terraformCloudAccessCredentialsRead(d, meta)
+}
+
+// terraformCloudAccessCredentialsResourceRead only confirms the lease this
+// resource already minted is still known to Vault. It must not rotate the
+// credential itself: Read runs on every `terraform plan`/refresh, including
+// ahead of a `terraform destroy`, and minting a replacement token here would
+// orphan the one already recorded in state without ever revoking it.
+// Rotation only happens through Create, driven by a `terraform taint` (or
+// equivalent forced replacement) that goes through Delete first.
+func terraformCloudAccessCredentialsResourceRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(*api.Client)
+
+	if _, err := client.Sys().Lookup(d.Id()); err != nil {
+		log.Printf("[WARN] Terraform Cloud credentials lease %q not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func terraformCloudAccessCredentialsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	leaseID := d.Id()
+
+	log.Printf("[DEBUG] Revoking Terraform Cloud credentials lease %q", leaseID)
+	if err := client.Sys().Revoke(leaseID); err != nil {
+		return fmt.Errorf("error revoking Terraform Cloud credentials lease %q: %s", leaseID, err)
+	}
+	log.Printf("[DEBUG] Revoked Terraform Cloud credentials lease %q", leaseID)
+	d.SetId("")
+
+	return nil
+}
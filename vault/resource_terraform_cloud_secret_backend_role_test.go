@@ -0,0 +1,132 @@
+package vault
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestTerraformCloudAccessCredentialsNeedsRefresh(t *testing.T) {
+	sch := terraformCloudAccessCredentialsSchema(false)
+
+	cases := map[string]struct {
+		leaseStarted  string
+		leaseDuration int
+		want          bool
+	}{
+		"no lease recorded yet": {
+			leaseStarted:  "",
+			leaseDuration: 0,
+			want:          true,
+		},
+		"freshly issued, far from expiry": {
+			leaseStarted:  time.Now().UTC().Format(time.RFC3339),
+			leaseDuration: 3600,
+			want:          false,
+		},
+		"already past expiry": {
+			leaseStarted:  time.Now().Add(-61 * time.Minute).UTC().Format(time.RFC3339),
+			leaseDuration: 3600,
+			want:          true,
+		},
+		"inside the renewal threshold but not yet expired": {
+			leaseStarted:  time.Now().Add(-59*time.Minute - 30*time.Second).UTC().Format(time.RFC3339),
+			leaseDuration: 3600,
+			want:          true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, sch, map[string]interface{}{
+				"lease_started":  tc.leaseStarted,
+				"lease_duration": tc.leaseDuration,
+			})
+
+			if got := terraformCloudAccessCredentialsNeedsRefresh(d); got != tc.want {
+				t.Fatalf("expected needs-refresh=%v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestAccTerraformCloudAccessCredentials_dataSourceAndResource exercises the
+// data source and resource CRUD wiring against a real Terraform Cloud
+// secrets backend. It is skipped unless TF_ACC and a real Terraform Cloud
+// organization/token are available.
+func TestAccTerraformCloudAccessCredentials_dataSourceAndResource(t *testing.T) {
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar)
+	}
+
+	organization := os.Getenv("TERRAFORM_CLOUD_ORGANIZATION")
+	token := os.Getenv("TERRAFORM_CLOUD_TOKEN")
+	if organization == "" || token == "" {
+		t.Skip("TERRAFORM_CLOUD_ORGANIZATION and TERRAFORM_CLOUD_TOKEN must be set for this test")
+	}
+
+	testAccPreCheck(t)
+	client := testProvider.Meta().(*api.Client)
+
+	backend := acctest.RandomWithPrefix("tf-test-terraform-cloud")
+	if err := client.Sys().Mount(backend, &api.MountInput{Type: "terraform"}); err != nil {
+		t.Fatalf("error mounting terraform cloud secrets backend at %q: %s", backend, err)
+	}
+	defer client.Sys().Unmount(backend)
+
+	if _, err := client.Logical().Write(backend+"/config", map[string]interface{}{
+		"token": token,
+	}); err != nil {
+		t.Fatalf("error configuring terraform cloud secrets backend at %q: %s", backend, err)
+	}
+
+	roleName := acctest.RandomWithPrefix("tf-test-role")
+	if _, err := client.Logical().Write(terraformCloudSecretBackendRolePath(backend, roleName), map[string]interface{}{
+		"organization": organization,
+	}); err != nil {
+		t.Fatalf("error creating terraform cloud secret backend role %q: %s", roleName, err)
+	}
+
+	d := schema.TestResourceDataRaw(t, terraformCloudAccessCredentialsDataSource().Schema, map[string]interface{}{
+		"backend": backend,
+		"role":    roleName,
+	})
+	if err := terraformCloudAccessCredentialsRead(d, client); err != nil {
+		t.Fatalf("error reading terraform cloud access credentials: %s", err)
+	}
+	if d.Get("token").(string) == "" {
+		t.Fatal("expected a non-empty token from the data source")
+	}
+
+	rd := schema.TestResourceDataRaw(t, terraformCloudAccessCredentialsResource().Schema, map[string]interface{}{
+		"backend": backend,
+		"role":    roleName,
+	})
+	if err := terraformCloudAccessCredentialsCreate(rd, client); err != nil {
+		t.Fatalf("error creating terraform cloud access credentials resource: %s", err)
+	}
+	if rd.Id() == "" {
+		t.Fatal("expected the resource to have an ID after create")
+	}
+
+	if err := terraformCloudAccessCredentialsResourceRead(rd, client); err != nil {
+		t.Fatalf("error refreshing terraform cloud access credentials resource: %s", err)
+	}
+	if rd.Id() == "" {
+		t.Fatal("expected the lease to still be present immediately after create")
+	}
+
+	leaseID := rd.Id()
+	if err := terraformCloudAccessCredentialsDelete(rd, client); err != nil {
+		t.Fatalf("error deleting terraform cloud access credentials resource: %s", err)
+	}
+	if _, err := client.Sys().Lookup(leaseID); err == nil {
+		t.Fatal("expected the lease to be revoked after delete")
+	}
+}